@@ -0,0 +1,62 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/kubernetes/kompose/pkg/app"
+)
+
+var deployOpt app.DeployOptions
+
+// upCmd runs the same transformation Convert does, but applies the result
+// to a live cluster instead of printing it - analogous to `docker stack
+// deploy` against Kubernetes/OpenShift.
+var upCmd = &cobra.Command{
+	Use:     "up",
+	Aliases: []string{"deploy"},
+	Short:   "Convert and deploy to a Kubernetes cluster",
+	Long:    `'kompose up' runs the same conversion as 'kompose convert' and applies the resulting objects to a cluster via server-side apply.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		deployOpt.ConvertOptions = ConvertOpt
+		return app.ValidateFlags(args, cmd, &deployOpt.ConvertOptions)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if deployOpt.StackName == "" {
+			deployOpt.StackName = "kompose"
+		}
+		if err := app.Deploy(deployOpt); err != nil {
+			log.Fatalf("%v", err)
+		}
+	},
+}
+
+func init() {
+	upCmd.Flags().StringVar(&deployOpt.StackName, "name", "", "Stack name used for the kompose.io/stack label (defaults to 'kompose')")
+	upCmd.Flags().StringVar(&deployOpt.Namespace, "namespace", "", "Kubernetes namespace to deploy into")
+	upCmd.Flags().StringVar(&deployOpt.KubeConfig, "kubeconfig", "", "Path to the kubeconfig file to use")
+	upCmd.Flags().StringVar(&deployOpt.Context, "context", "", "Kubeconfig context to use")
+	upCmd.Flags().StringVar(&deployOpt.DryRun, "dry-run", "", "Must be 'server' or 'client'. If set, only show what would be applied")
+	upCmd.Flags().BoolVar(&deployOpt.Wait, "wait", false, "Wait for applied objects to become ready")
+	upCmd.Flags().DurationVar(&deployOpt.Timeout, "timeout", 5*time.Minute, "How long to wait for --wait before giving up")
+
+	RootCmd.AddCommand(upCmd)
+}
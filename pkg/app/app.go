@@ -27,6 +27,10 @@ import (
 
 	"github.com/kubernetes/kompose/pkg/kobject"
 	"github.com/kubernetes/kompose/pkg/loader"
+	// Registers the "dab" loader with pkg/loader; nothing here references
+	// the package directly, so it's imported for its init() side effect.
+	_ "github.com/kubernetes/kompose/pkg/loader/bundle"
+	"github.com/kubernetes/kompose/pkg/loader/compose"
 	"github.com/kubernetes/kompose/pkg/transformer"
 	"github.com/kubernetes/kompose/pkg/transformer/kubernetes"
 	"github.com/kubernetes/kompose/pkg/transformer/openshift"
@@ -49,9 +53,31 @@ const (
 	ProviderOpenshift = "openshift"
 	// DefaultProvider - provider that will be used if there is no provider was explicitly set
 	DefaultProvider = ProviderKubernetes
+
+	// OutFormatKustomize emits a Kustomize base + overlays instead of flat manifests
+	OutFormatKustomize = "kustomize"
+	// OutFormatHelm emits a templated Helm chart instead of flat manifests
+	OutFormatHelm = "helm"
+)
+
+const (
+	// InputFormatCompose is the default, docker-compose YAML input
+	InputFormatCompose = "compose"
+	// InputFormatBundle is a Distributed Application Bundle (DAB) JSON input
+	InputFormatBundle = "dab"
 )
 
-var inputFormat = "compose"
+// lookupFlagString returns the string value of a registered flag, or def if
+// the command doesn't register it. Not every command that runs through
+// ValidateFlags registers every flag it checks (e.g. a minimal third-party
+// entry point), so Lookup returning nil isn't itself an error.
+func lookupFlagString(cmd *cobra.Command, name, def string) string {
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil {
+		return def
+	}
+	return flag.Value.String()
+}
 
 // ValidateFlags validates all command line flags
 func ValidateFlags(args []string, cmd *cobra.Command, opt *kobject.ConvertOptions) error {
@@ -64,54 +90,62 @@ func ValidateFlags(args []string, cmd *cobra.Command, opt *kobject.ConvertOption
 	provider := cmd.Flags().Lookup("provider").Value.String()
 	log.Debugf("Checking validation of provider: %s", provider)
 
-	// OpenShift specific flags
-	deploymentConfig := cmd.Flags().Lookup("deployment-config").Changed
-	buildRepo := cmd.Flags().Lookup("build-repo").Changed
-	buildBranch := cmd.Flags().Lookup("build-branch").Changed
+	asTemplate := lookupFlagString(cmd, "as-template", "")
 
-	// Kubernetes specific flags
-	chart := cmd.Flags().Lookup("chart").Changed
-	daemonSet := cmd.Flags().Lookup("daemon-set").Changed
-	replicationController := cmd.Flags().Lookup("replication-controller").Changed
-	deployment := cmd.Flags().Lookup("deployment").Changed
+	// Output format specific flags
+	outFormat := lookupFlagString(cmd, "out-format", "")
+	overlay := lookupFlagString(cmd, "overlay", "")
+
+	// Input format override
+	inputFormat := lookupFlagString(cmd, "input-format", "")
+
+	// Compose profiles to activate; not every command that runs through
+	// ValidateFlags registers --profile, so a missing flag just means no
+	// profiles were requested rather than an error.
+	profiles, err := cmd.Flags().GetStringArray("profile")
+	if err != nil {
+		profiles = nil
+	}
+	opt.Profiles = profiles
 
 	// Get the controller
 	controller := opt.Controller
 	log.Debugf("Checking validation of controller: %s", controller)
 
-	// Check validations against provider flags
-	switch {
-	case provider == ProviderOpenshift:
-		if chart {
-			return fmt.Errorf("--chart, -c is a Kubernetes only flag")
-		}
-		if daemonSet {
-			return fmt.Errorf("--daemon-set is a Kubernetes only flag")
-		}
-		if replicationController {
-			return fmt.Errorf("--replication-controller is a Kubernetes only flag")
+	// Check validations against provider flags: any flag that belongs
+	// exclusively to a different registered provider is rejected.
+	if _, ok := transformer.Get(provider); !ok {
+		return fmt.Errorf("Unknown provider: %q, possible values are: %s", provider, strings.Join(transformer.Names(), ", "))
+	}
+	for _, name := range transformer.Names() {
+		if name == provider {
+			continue
 		}
-		if deployment {
-			return fmt.Errorf("--deployment, -d is a Kubernetes only flag")
+		other, _ := transformer.Get(name)
+		for _, flagName := range other.Flags {
+			// A third-party provider can register Flags that were never
+			// added to cmd's FlagSet; only enforce exclusivity for flags
+			// that actually exist on this command.
+			flag := cmd.Flags().Lookup(flagName)
+			if flag != nil && flag.Changed {
+				return fmt.Errorf("--%s is a %s only flag", flagName, name)
+			}
 		}
+	}
+
+	switch provider {
+	case ProviderOpenshift:
 		if controller == "daemonset" || controller == "replicationcontroller" || controller == "deployment" {
 			return fmt.Errorf("--controller= daemonset, replicationcontroller or deployment is a Kubernetes only flag")
 		}
-	case provider == ProviderKubernetes:
-		if deploymentConfig {
-			return fmt.Errorf("--deployment-config is an OpenShift only flag")
-		}
-		if buildRepo {
-			return fmt.Errorf("--build-repo is an Openshift only flag")
-		}
-		if buildBranch {
-			return fmt.Errorf("--build-branch is an Openshift only flag")
-		}
+	case ProviderKubernetes:
 		if controller == "deploymentconfig" {
 			return fmt.Errorf("--controller=deploymentConfig is an OpenShift only flag")
 		}
 	}
 
+	opt.AsTemplate = asTemplate
+
 	// Standard checks regardless of provider
 	if len(opt.OutFile) != 0 && opt.ToStdout {
 		return fmt.Errorf("Error: --out and --stdout can't be set at the same time")
@@ -133,6 +167,26 @@ func ValidateFlags(args []string, cmd *cobra.Command, opt *kobject.ConvertOption
 		return fmt.Errorf("YAML and JSON format cannot be provided at the same time")
 	}
 
+	switch outFormat {
+	case "", OutFormatKustomize, OutFormatHelm:
+	default:
+		return fmt.Errorf("Error: --out-format must be one of '%s' or '%s'", OutFormatKustomize, OutFormatHelm)
+	}
+
+	switch inputFormat {
+	case "", InputFormatCompose, InputFormatBundle:
+	default:
+		return fmt.Errorf("Error: --input-format must be one of '%s' or '%s'", InputFormatCompose, InputFormatBundle)
+	}
+	opt.InputFormat = inputFormat
+
+	if overlay != "" && outFormat != OutFormatKustomize {
+		return fmt.Errorf("Error: --overlay can only be used together with --out-format=%s", OutFormatKustomize)
+	}
+
+	opt.OutFormat = outFormat
+	opt.Overlay = overlay
+
 	if _, ok := kubernetes.ValidVolumeSet[opt.Volumes]; !ok {
 		validVolumesTypes := make([]string, 0)
 		for validVolumeType := range kubernetes.ValidVolumeSet {
@@ -203,22 +257,61 @@ func validateControllers(opt *kobject.ConvertOptions) error {
 	return nil
 }
 
-// Convert transforms docker compose or dab file to k8s objects
-func Convert(opt kobject.ConvertOptions) error {
-	err := validateControllers(&opt)
-	if err != nil {
-		return err
+// loadKomposeObject loads opt.InputFiles through the loader registered for
+// opt.InputFormat (or the auto-detected format), applying the same
+// override-file merge and --profile filtering regardless of entry point, so
+// Convert and buildObjects (used by `kompose up`/`deploy`) stay in sync.
+func loadKomposeObject(opt kobject.ConvertOptions) (kobject.KomposeObject, error) {
+	format := opt.InputFormat
+	if format == "" {
+		format = detectInputFormat(opt.InputFiles)
 	}
-	// loader parses input from file into komposeObject.
-	l, err := loader.GetLoader(inputFormat)
+	l, err := loader.GetLoader(format)
 	if err != nil {
-		return err
+		return kobject.KomposeObject{}, err
 	}
 
 	komposeObject := kobject.KomposeObject{
 		ServiceConfigs: make(map[string]kobject.ServiceConfig),
 	}
-	komposeObject, err = l.LoadFile(opt.InputFiles)
+	if format == InputFormatCompose && len(opt.InputFiles) > 1 {
+		// Compose's override-file precedence (docker-compose.yml, then
+		// docker-compose.override.yml, then any `-f` extras) is applied
+		// across files, not within a single loader call, so each file is
+		// loaded on its own and merged in the order given.
+		loaded := make([]kobject.KomposeObject, 0, len(opt.InputFiles))
+		for _, file := range opt.InputFiles {
+			obj, err := l.LoadFile([]string{file})
+			if err != nil {
+				return kobject.KomposeObject{}, err
+			}
+			loaded = append(loaded, obj)
+		}
+		komposeObject = compose.MergeOverrides(loaded)
+	} else {
+		komposeObject, err = l.LoadFile(opt.InputFiles)
+		if err != nil {
+			return kobject.KomposeObject{}, err
+		}
+	}
+
+	// Compose profiles gate which services are included; this is a
+	// post-load filter applied after override-file merging above.
+	if format == InputFormatCompose {
+		komposeObject = compose.FilterProfiles(komposeObject, opt.Profiles)
+	}
+
+	return komposeObject, nil
+}
+
+// Convert transforms docker compose or dab file to k8s objects
+func Convert(opt kobject.ConvertOptions) error {
+	err := validateControllers(&opt)
+	if err != nil {
+		return err
+	}
+
+	komposeObject, err := loadKomposeObject(opt)
 	if err != nil {
 		return err
 	}
@@ -233,6 +326,19 @@ func Convert(opt kobject.ConvertOptions) error {
 		return err
 	}
 
+	if opt.AsTemplate != "" {
+		objects, err = openshift.WrapAsTemplate(objects, komposeObject, opt.AsTemplate)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Emit a templated Helm chart or a Kustomize base+overlay instead of flat
+	// manifests when requested.
+	if opt.OutFormat != "" {
+		return printPostProcessed(objects, komposeObject, opt)
+	}
+
 	// Print output
 	err = kubernetes.PrintList(objects, opt)
 	if err != nil {
@@ -242,16 +348,14 @@ func Convert(opt kobject.ConvertOptions) error {
 }
 
 // Convenience method to return the appropriate Transformer based on
-// what provider we are using.
+// what provider we are using. Providers register themselves with
+// transformer.Register, so out-of-tree providers work here too.
 func getTransformer(opt kobject.ConvertOptions) transformer.Transformer {
-	var t transformer.Transformer
-	if opt.Provider == DefaultProvider {
-		// Create/Init new Kubernetes object with CLI opts
-		t = &kubernetes.Kubernetes{Opt: opt}
-	} else {
-		// Create/Init new OpenShift object that is initialized with a newly
-		// created Kubernetes object. Openshift inherits from Kubernetes
-		t = &openshift.OpenShift{Kubernetes: kubernetes.Kubernetes{Opt: opt}}
+	if provider, ok := transformer.Get(opt.Provider); ok {
+		return provider.Factory(opt)
 	}
-	return t
+	// ValidateFlags already rejects unregistered providers; fall back to
+	// the default one defensively.
+	provider, _ := transformer.Get(DefaultProvider)
+	return provider.Factory(opt)
 }
@@ -0,0 +1,343 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+)
+
+// fieldManager is the server-side apply field manager kompose identifies
+// itself with, so re-applies from kompose can always reconcile their own
+// fields regardless of what else touched the object in between.
+const fieldManager = "kompose"
+
+// stackLabel marks every object kompose deploys for a given compose
+// project, so a later `kompose deploy` can prune services that were
+// removed from the compose file.
+const stackLabel = "kompose.io/stack"
+
+// DeployOptions holds the flags specific to `kompose deploy`/`kompose up`,
+// on top of the regular kobject.ConvertOptions used by Convert.
+type DeployOptions struct {
+	kobject.ConvertOptions
+
+	StackName  string
+	Namespace  string
+	KubeConfig string
+	Context    string
+	DryRun     string
+	Wait       bool
+	Timeout    time.Duration
+}
+
+// Deploy runs the same pipeline as Convert but, instead of printing the
+// objects, applies them to a live cluster via server-side apply, roughly
+// analogous to `docker stack deploy` against Kubernetes/OpenShift: an
+// ordered apply (Namespace -> RBAC -> workloads), an optional wait for
+// readiness, and pruning of services removed from the compose file.
+func Deploy(opt DeployOptions) error {
+	objects, err := buildObjects(opt.ConvertOptions)
+	if err != nil {
+		return err
+	}
+	labelWithStack(objects, opt.StackName)
+
+	client, err := newDynamicClient(opt.KubeConfig, opt.Context)
+	if err != nil {
+		return err
+	}
+
+	ordered := orderForApply(objects)
+	for _, obj := range ordered {
+		if err := applyObject(client, obj, opt); err != nil {
+			return err
+		}
+	}
+
+	if err := pruneRemoved(client, ordered, opt); err != nil {
+		return err
+	}
+
+	if opt.Wait {
+		if err := waitForReady(client, ordered, opt); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Deployed %d object(s) for stack %q", len(ordered), opt.StackName)
+	return nil
+}
+
+// buildObjects runs the same load+merge+filter+transform steps Convert
+// uses, stopping short of printing, so Deploy can apply the objects
+// instead. Sharing loadKomposeObject keeps multi-file override merging and
+// --profile filtering working for `kompose up`/`deploy`, not just convert.
+func buildObjects(opt kobject.ConvertOptions) ([]runtime.Object, error) {
+	if err := validateControllers(&opt); err != nil {
+		return nil, err
+	}
+
+	komposeObject, err := loadKomposeObject(opt)
+	if err != nil {
+		return nil, err
+	}
+
+	t := getTransformer(opt)
+	return t.Transform(komposeObject, opt)
+}
+
+func labelWithStack(objects []runtime.Object, stackName string) {
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		labels := accessor.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[stackLabel] = stackName
+		accessor.SetLabels(labels)
+	}
+}
+
+// applyOrder ranks the kinds kompose commonly emits the way `docker stack
+// deploy` orders them: namespaces/CRDs/RBAC before the workloads that may
+// depend on them.
+var applyOrder = map[string]int{
+	"Namespace":                0,
+	"CustomResourceDefinition": 1,
+	"ServiceAccount":           2,
+	"Role":                     2,
+	"RoleBinding":              2,
+	"ClusterRole":              2,
+	"ClusterRoleBinding":       2,
+	"ConfigMap":                3,
+	"Secret":                   3,
+	"Service":                  4,
+}
+
+// defaultApplyPriority is used for workload kinds (Deployment, StatefulSet,
+// DaemonSet, Job, ...) and anything kompose doesn't special-case.
+const defaultApplyPriority = 5
+
+func orderForApply(objects []runtime.Object) []runtime.Object {
+	ordered := append([]runtime.Object{}, objects...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return applyPriority(ordered[i]) < applyPriority(ordered[j])
+	})
+	return ordered
+}
+
+func applyPriority(obj runtime.Object) int {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if p, ok := applyOrder[kind]; ok {
+		return p
+	}
+	return defaultApplyPriority
+}
+
+func newDynamicClient(kubeconfig, kubeContext string) (dynamic.Interface, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		rules.ExplicitPath = kubeconfig
+	}
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("Error loading kubeconfig: %v", err)
+	}
+	return dynamic.NewForConfig(config)
+}
+
+// resourceFor maps an object's GroupVersionKind to a (plural, lower-cased)
+// GroupVersionResource, which is good enough for the built-in kinds kompose
+// generates without needing full REST mapper discovery.
+func resourceFor(obj runtime.Object) (schema.GroupVersionResource, string, string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return schema.GroupVersionResource{}, "", "", err
+	}
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	gvr := schema.GroupVersionResource{
+		Group:    gvk.Group,
+		Version:  gvk.Version,
+		Resource: pluralKind(gvk.Kind),
+	}
+	return gvr, accessor.GetNamespace(), accessor.GetName(), nil
+}
+
+// irregularPlurals holds the built-in kinds kompose emits whose REST
+// resource name doesn't follow the simple lower-case-and-append-"s" rule.
+var irregularPlurals = map[string]string{
+	"Ingress":           "ingresses",
+	"NetworkPolicy":     "networkpolicies",
+	"PriorityClass":     "priorityclasses",
+	"StorageClass":      "storageclasses",
+	"PodSecurityPolicy": "podsecuritypolicies",
+}
+
+func pluralKind(kind string) string {
+	if plural, ok := irregularPlurals[kind]; ok {
+		return plural
+	}
+	lower := []rune(kind)
+	for i, r := range lower {
+		if r >= 'A' && r <= 'Z' {
+			lower[i] = r + ('a' - 'A')
+		}
+	}
+	return string(lower) + "s"
+}
+
+func applyObject(client dynamic.Interface, obj runtime.Object, opt DeployOptions) error {
+	gvr, namespace, name, err := resourceFor(obj)
+	if err != nil {
+		return err
+	}
+	if namespace == "" {
+		namespace = deployNamespace(opt)
+	}
+
+	u, err := toUnstructuredJSON(obj)
+	if err != nil {
+		return err
+	}
+
+	patchOpts := metav1.PatchOptions{FieldManager: fieldManager, Force: boolPtr(true)}
+	if opt.DryRun == "server" {
+		patchOpts.DryRun = []string{metav1.DryRunAll}
+	} else if opt.DryRun == "client" {
+		log.Infof("client dry-run: would apply %s %q", gvr.Resource, name)
+		return nil
+	}
+
+	_, err = client.Resource(gvr).Namespace(namespace).Patch(context.Background(), name, types.ApplyPatchType, u, patchOpts)
+	if err != nil {
+		return fmt.Errorf("Error applying %s %q: %v", gvr.Resource, name, err)
+	}
+	return nil
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+// deployNamespace returns the namespace to deploy into when an object (or
+// opt.Namespace) doesn't specify one, falling back to "default" the same way
+// kubectl does when no --namespace is given.
+func deployNamespace(opt DeployOptions) string {
+	if opt.Namespace != "" {
+		return opt.Namespace
+	}
+	return "default"
+}
+
+// pruneRemoved deletes previously deployed objects for this stack that are
+// no longer present in the current compose file, matched by stackLabel. It
+// is itself subject to opt.DryRun: a dry run must never mutate the cluster.
+func pruneRemoved(client dynamic.Interface, desired []runtime.Object, opt DeployOptions) error {
+	namespace := deployNamespace(opt)
+
+	keep := map[string]bool{}
+	kinds := map[schema.GroupVersionResource]bool{}
+	for _, obj := range desired {
+		gvr, objNamespace, name, err := resourceFor(obj)
+		if err != nil {
+			continue
+		}
+		if objNamespace == "" {
+			objNamespace = namespace
+		}
+		keep[gvr.Resource+"/"+objNamespace+"/"+name] = true
+		kinds[gvr] = true
+	}
+
+	deleteOpts := metav1.DeleteOptions{}
+	if opt.DryRun == "server" {
+		deleteOpts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	for gvr := range kinds {
+		list, err := client.Resource(gvr).Namespace(namespace).List(context.Background(), metav1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", stackLabel, opt.StackName),
+		})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		for _, item := range list.Items {
+			key := gvr.Resource + "/" + item.GetNamespace() + "/" + item.GetName()
+			if keep[key] {
+				continue
+			}
+			if opt.DryRun == "client" {
+				log.Infof("client dry-run: would prune %s %q removed from stack %q", gvr.Resource, item.GetName(), opt.StackName)
+				continue
+			}
+			log.Infof("Pruning %s %q removed from stack %q", gvr.Resource, item.GetName(), opt.StackName)
+			if err := client.Resource(gvr).Namespace(item.GetNamespace()).Delete(context.Background(), item.GetName(), deleteOpts); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// waitForReady polls until every applied object reports ready, or timeout
+// elapses. Readiness for built-in workload kinds is approximated via the
+// observed status fields populated by their controllers.
+func waitForReady(client dynamic.Interface, objects []runtime.Object, opt DeployOptions) error {
+	deadline := time.Now().Add(opt.Timeout)
+	for _, obj := range objects {
+		gvr, namespace, name, err := resourceFor(obj)
+		if err != nil {
+			continue
+		}
+		if namespace == "" {
+			namespace = deployNamespace(opt)
+		}
+		for {
+			u, err := client.Resource(gvr).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+			if err == nil && isReady(u) {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("Timed out waiting for %s %q to become ready", gvr.Resource, name)
+			}
+			time.Sleep(time.Second)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// toUnstructuredJSON re-encodes a typed object as the JSON payload the
+// dynamic client's Patch expects for a server-side apply.
+func toUnstructuredJSON(obj runtime.Object) ([]byte, error) {
+	return json.Marshal(obj)
+}
+
+// isReady reports whether an applied object's observed status indicates it
+// is ready to serve. It understands the handful of status shapes kompose's
+// own generated kinds use (Deployment/StatefulSet-style readyReplicas,
+// DaemonSet numberReady, and the phase of plain Pods); anything else is
+// considered ready as soon as it exists, since we have no generic notion of
+// readiness for it.
+func isReady(u *unstructured.Unstructured) bool {
+	switch u.GetKind() {
+	case "DaemonSet":
+		desired, found, err := unstructured.NestedInt64(u.Object, "status", "desiredNumberScheduled")
+		if err != nil || !found {
+			return false
+		}
+		numberReady, _, _ := unstructured.NestedInt64(u.Object, "status", "numberReady")
+		return numberReady >= desired
+	case "Pod":
+		phase, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+		return phase == "Running"
+	}
+
+	replicas, found, err := unstructured.NestedInt64(u.Object, "spec", "replicas")
+	if err != nil || !found {
+		return true
+	}
+
+	ready, found, err := unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+	if err != nil || !found {
+		return false
+	}
+
+	return ready >= replicas
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// detectInputFormat guesses the input format from the first input file's
+// extension, falling back to sniffing its content. It defaults to
+// InputFormatCompose when nothing more specific is found.
+func detectInputFormat(files []string) string {
+	if len(files) == 0 {
+		return InputFormatCompose
+	}
+
+	switch strings.ToLower(filepath.Ext(files[0])) {
+	case ".dab":
+		return InputFormatBundle
+	case ".yml", ".yaml":
+		return InputFormatCompose
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		return InputFormatCompose
+	}
+
+	var probe struct {
+		Version  string                     `json:"Version"`
+		Services map[string]json.RawMessage `json:"Services"`
+	}
+	if json.Unmarshal(data, &probe) == nil && probe.Services != nil {
+		return InputFormatBundle
+	}
+
+	return InputFormatCompose
+}
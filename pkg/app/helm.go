@@ -0,0 +1,198 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/kubernetes/kompose/pkg/transformer/kubernetes"
+)
+
+// helmValues is the values.yaml kompose derives from the compose file: one
+// entry per service, holding the settings that are most likely to vary
+// between environments.
+type helmValues map[string]helmServiceValues
+
+type helmServiceValues struct {
+	Image     string            `yaml:"image"`
+	Replicas  int               `yaml:"replicas"`
+	Env       map[string]string `yaml:"env,omitempty"`
+	Resources helmResources     `yaml:"resources,omitempty"`
+}
+
+type helmResources struct {
+	Limits map[string]string `yaml:"limits,omitempty"`
+}
+
+// helmValuesFromCompose hoists env vars, image tags, replicas and resource
+// limits out of the compose service configs into a values.yaml.
+func helmValuesFromCompose(komposeObject kobject.KomposeObject) helmValues {
+	values := make(helmValues, len(komposeObject.ServiceConfigs))
+	for name, svc := range komposeObject.ServiceConfigs {
+		env := make(map[string]string, len(svc.Environment))
+		for _, e := range svc.Environment {
+			env[e.Name] = e.Value
+		}
+
+		values[name] = helmServiceValues{
+			Image:    svc.Image,
+			Replicas: svc.Replicas,
+			Env:      env,
+			Resources: helmResources{
+				Limits: map[string]string{
+					"cpu":    svc.CPULimit,
+					"memory": svc.MemLimit,
+				},
+			},
+		}
+	}
+	return values
+}
+
+// templatizeObjects rewrites hoisted fields on the transformed objects into
+// `{{ .Values.<service>.<field> }}` references so they can be shipped as
+// Helm templates rather than static manifests. Objects are matched to a
+// service by name, which is how kompose names the objects it generates for
+// a given compose service; objects with nothing to hoist (e.g. Services)
+// pass through unchanged.
+func templatizeObjects(objects []runtime.Object, values helmValues) ([]runtime.Object, error) {
+	out := make([]runtime.Object, 0, len(objects))
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		svc, ok := values[accessor.GetName()]
+		if !ok {
+			out = append(out, obj)
+			continue
+		}
+
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		templatizeWorkload(m, accessor.GetName(), svc)
+		out = append(out, &unstructured.Unstructured{Object: m})
+	}
+	return out, nil
+}
+
+// templatizeWorkload substitutes the replicas, container image, env values
+// and resource limits of a workload's unstructured representation with
+// `{{ .Values.<service>.<field> }}` references, when present.
+func templatizeWorkload(m map[string]interface{}, name string, svc helmServiceValues) {
+	if _, found, _ := unstructured.NestedInt64(m, "spec", "replicas"); found {
+		_ = unstructured.SetNestedField(m, fmt.Sprintf("{{ .Values.%s.replicas }}", name), "spec", "replicas")
+	}
+
+	containers, found, _ := unstructured.NestedSlice(m, "spec", "template", "spec", "containers")
+	if !found || len(containers) == 0 {
+		return
+	}
+	container, ok := containers[0].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if _, found := container["image"]; found {
+		container["image"] = fmt.Sprintf("{{ .Values.%s.image }}", name)
+	}
+
+	if env, found := container["env"].([]interface{}); found {
+		for _, e := range env {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			envName, _ := entry["name"].(string)
+			if _, hoisted := svc.Env[envName]; hoisted {
+				entry["value"] = fmt.Sprintf("{{ .Values.%s.env.%s }}", name, envName)
+			}
+		}
+	}
+
+	if cpu, found, _ := unstructured.NestedString(container, "resources", "limits", "cpu"); found && cpu != "" {
+		_ = unstructured.SetNestedField(container, fmt.Sprintf("{{ .Values.%s.resources.limits.cpu }}", name), "resources", "limits", "cpu")
+	}
+	if mem, found, _ := unstructured.NestedString(container, "resources", "limits", "memory"); found && mem != "" {
+		_ = unstructured.SetNestedField(container, fmt.Sprintf("{{ .Values.%s.resources.limits.memory }}", name), "resources", "limits", "memory")
+	}
+
+	containers[0] = container
+	_ = unstructured.SetNestedSlice(m, containers, "spec", "template", "spec", "containers")
+}
+
+// quotedReplicasTemplate matches the `replicas: "{{ .Values.<svc>.replicas }}"`
+// line PrintList's YAML marshaling produces for the template string set
+// above. replicas is an integer field, so the quotes must be stripped or
+// the rendered manifest fails API validation once Helm substitutes a number
+// in for the expression.
+var quotedReplicasTemplate = regexp.MustCompile(`replicas: ["']({{ \.Values\.[^"']+}})["']`)
+
+func unquoteReplicasTemplate(data []byte) []byte {
+	return quotedReplicasTemplate.ReplaceAll(data, []byte(`replicas: $1`))
+}
+
+// writeChart lays out a standard Helm chart directory: Chart.yaml,
+// values.yaml and templates/.
+func writeChart(outDir string, values helmValues, templates []runtime.Object) error {
+	chartDir := filepath.Join(outDir, "chart")
+	templatesDir := filepath.Join(chartDir, "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return fmt.Errorf("Error creating Helm chart directory %q: %v", chartDir, err)
+	}
+
+	chartYaml := fmt.Sprintf("apiVersion: v1\nname: %s\nversion: 0.0.1\n", filepath.Base(chartDir))
+	if err := os.WriteFile(filepath.Join(chartDir, "Chart.yaml"), []byte(chartYaml), 0644); err != nil {
+		return err
+	}
+
+	valuesData, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(chartDir, "values.yaml"), valuesData, 0644); err != nil {
+		return err
+	}
+
+	if len(templates) == 0 {
+		return nil
+	}
+
+	resourcesFile := filepath.Join(templatesDir, "resources.yaml")
+	resourceOpt := kobject.ConvertOptions{OutFile: resourcesFile}
+	if err := kubernetes.PrintList(templates, resourceOpt); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(resourcesFile)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(resourcesFile, unquoteReplicasTemplate(data), 0644)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/kubernetes/kompose/pkg/transformer/kubernetes"
+)
+
+// kustomization is an in-memory representation of a Kustomize base or
+// overlay directory: the resources it references and the transformers
+// kompose inferred from the compose file.
+type kustomization struct {
+	// dir is the directory name the kustomization is written to, e.g.
+	// "base" or "overlays/staging".
+	dir          string
+	resources    []runtime.Object
+	namespace    string
+	commonLabels map[string]string
+	images       []string
+	bases        []string
+}
+
+// buildKustomizationBase turns the transformed objects into a Kustomize
+// base directory. images is inferred from the compose service configs;
+// commonLabels is a fixed kompose marker, since Kustomize applies
+// commonLabels identically to every resource and per-service values
+// wouldn't make sense there.
+func buildKustomizationBase(objects []runtime.Object, komposeObject kobject.KomposeObject, opt kobject.ConvertOptions) (*kustomization, error) {
+	images := make([]string, 0, len(komposeObject.ServiceConfigs))
+	for _, svc := range komposeObject.ServiceConfigs {
+		if svc.Image != "" {
+			images = append(images, svc.Image)
+		}
+	}
+
+	return &kustomization{
+		dir:       "base",
+		resources: objects,
+		images:    images,
+		commonLabels: map[string]string{
+			"app.kubernetes.io/managed-by": "kompose",
+		},
+	}, nil
+}
+
+// buildKustomizationOverlay creates an overlay that patches the given base,
+// named after envName (e.g. "staging", "production").
+func buildKustomizationOverlay(envName string, base *kustomization) *kustomization {
+	return &kustomization{
+		dir:       filepath.Join("overlays", envName),
+		namespace: envName,
+		bases:     []string{filepath.Join("..", "..", base.dir)},
+	}
+}
+
+// write renders the kustomization.yaml and resource manifests for k under
+// outDir (the current directory when outDir is empty).
+func (k *kustomization) write(outDir string) error {
+	dir := filepath.Join(outDir, k.dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("Error creating kustomize directory %q: %v", dir, err)
+	}
+
+	if len(k.resources) > 0 {
+		resourceOpt := kobject.ConvertOptions{OutFile: filepath.Join(dir, "resources.yaml")}
+		if err := kubernetes.PrintList(k.resources, resourceOpt); err != nil {
+			return err
+		}
+	}
+
+	return writeKustomizationYaml(dir, k)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// kustomizationYaml mirrors the subset of the kustomization.yaml schema
+// kompose knows how to populate automatically.
+type kustomizationYaml struct {
+	APIVersion   string            `yaml:"apiVersion"`
+	Kind         string            `yaml:"kind"`
+	Resources    []string          `yaml:"resources,omitempty"`
+	Bases        []string          `yaml:"bases,omitempty"`
+	Namespace    string            `yaml:"namespace,omitempty"`
+	CommonLabels map[string]string `yaml:"commonLabels,omitempty"`
+	Images       []kustomizeImage  `yaml:"images,omitempty"`
+}
+
+type kustomizeImage struct {
+	Name    string `yaml:"name"`
+	NewName string `yaml:"newName,omitempty"`
+}
+
+// writeKustomizationYaml renders k's kustomization.yaml into dir.
+func writeKustomizationYaml(dir string, k *kustomization) error {
+	out := kustomizationYaml{
+		APIVersion:   "kustomize.config.k8s.io/v1beta1",
+		Kind:         "Kustomization",
+		Bases:        k.bases,
+		Namespace:    k.namespace,
+		CommonLabels: k.commonLabels,
+	}
+
+	if len(k.resources) > 0 {
+		out.Resources = []string{"resources.yaml"}
+	}
+
+	images := append([]string{}, k.images...)
+	sort.Strings(images)
+	for _, img := range images {
+		out.Images = append(out.Images, kustomizeImage{Name: img})
+	}
+
+	data, err := yaml.Marshal(out)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "kustomization.yaml"), data, 0644)
+}
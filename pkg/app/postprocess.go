@@ -0,0 +1,70 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+)
+
+// printPostProcessed routes the transformed objects through a templating
+// pass instead of printing them as-is, based on opt.OutFormat.
+func printPostProcessed(objects []runtime.Object, komposeObject kobject.KomposeObject, opt kobject.ConvertOptions) error {
+	switch opt.OutFormat {
+	case OutFormatKustomize:
+		return writeKustomizeBase(objects, komposeObject, opt)
+	case OutFormatHelm:
+		return writeHelmChart(objects, komposeObject, opt)
+	default:
+		return fmt.Errorf("Unknown out-format: %s", opt.OutFormat)
+	}
+}
+
+// writeKustomizeBase emits a Kustomize base plus, when --overlay is set, a
+// per-environment overlay directory with common transformers (namespace,
+// commonLabels, images) inferred from the compose file.
+func writeKustomizeBase(objects []runtime.Object, komposeObject kobject.KomposeObject, opt kobject.ConvertOptions) error {
+	base, err := buildKustomizationBase(objects, komposeObject, opt)
+	if err != nil {
+		return err
+	}
+
+	if err := base.write(opt.OutFile); err != nil {
+		return err
+	}
+	if opt.Overlay == "" {
+		return nil
+	}
+
+	overlay := buildKustomizationOverlay(opt.Overlay, base)
+	return overlay.write(opt.OutFile)
+}
+
+// writeHelmChart emits a fuller Helm chart: a values.yaml derived from
+// compose env vars, image tags, replicas and resource limits, plus templated
+// manifests that reference those values.
+func writeHelmChart(objects []runtime.Object, komposeObject kobject.KomposeObject, opt kobject.ConvertOptions) error {
+	values := helmValuesFromCompose(komposeObject)
+	templates, err := templatizeObjects(objects, values)
+	if err != nil {
+		return err
+	}
+	return writeChart(opt.OutFile, values, templates)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kobject holds the types shared by every loader and transformer:
+// ConvertOptions carries the CLI-driven conversion settings, and
+// KomposeObject/ServiceConfig are the loader-agnostic representation of a
+// compose file that transformers turn into Kubernetes/OpenShift objects.
+package kobject
+
+// ConvertOptions holds the settings `kompose convert` (and anything built
+// on top of it, like `kompose up`) uses to drive loading, transforming and
+// printing.
+type ConvertOptions struct {
+	InputFiles  []string
+	InputFormat string
+	OutFile     string
+	ToStdout    bool
+
+	Provider   string
+	Controller string
+
+	CreateD                bool
+	CreateDS               bool
+	CreateRC               bool
+	CreateDeploymentConfig bool
+
+	CreateChart  bool
+	GenerateJSON bool
+	GenerateYaml bool
+	Replicas     int
+	Volumes      string
+
+	OutFormat  string
+	Overlay    string
+	AsTemplate string
+
+	Profiles []string
+}
+
+// KomposeObject is the loader-agnostic representation of a compose file (or
+// DAB bundle): one ServiceConfig per service, keyed by service name.
+type KomposeObject struct {
+	ServiceConfigs map[string]ServiceConfig
+}
+
+// ServiceConfig holds the settings loaded for a single compose service.
+type ServiceConfig struct {
+	Image         string
+	ContainerName string
+	Command       []string
+	Network       []string
+	Port          []Ports
+	Environment   []EnvVar
+	Volumes       []Volumes
+	Profiles      []string
+
+	Replicas  int
+	CPULimit  string
+	MemLimit  string
+
+	DeployConfig DeployConfig
+}
+
+// EnvVar is a single environment variable set on a service.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// Ports is a single published port, as kompose maps it onto a Kubernetes
+// Service/container port.
+type Ports struct {
+	HostPort      int32
+	ContainerPort int32
+	Protocol      string
+}
+
+// Volumes is a single volume mapping in `host:container[:mode]` form.
+type Volumes struct {
+	Host      string
+	Container string
+	Mode      string
+}
+
+// DeployConfig mirrors a compose `deploy:` block.
+type DeployConfig struct {
+	Replicas int
+	Resource Resource
+	Labels   map[string]string
+}
+
+// Resource mirrors a compose `deploy.resources:` block.
+type Resource struct {
+	Limit ResourceLimit
+}
+
+// ResourceLimit mirrors a compose `deploy.resources.limits:` block.
+type ResourceLimit struct {
+	CPU    string
+	Memory string
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundle loads a Docker Distributed Application Bundle (DAB) file
+// into a kobject.KomposeObject, mirroring what `docker stack deploy`
+// understood for bundle files.
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/kubernetes/kompose/pkg/loader"
+)
+
+func init() {
+	loader.Register("dab", Loader{})
+}
+
+// Bundle is the top level schema of a DAB file.
+type Bundle struct {
+	Version  string             `json:"Version"`
+	Services map[string]Service `json:"Services"`
+}
+
+// Service is a single service entry in a DAB file.
+type Service struct {
+	Image    string   `json:"Image"`
+	Command  []string `json:"Command,omitempty"`
+	Networks []string `json:"Networks,omitempty"`
+	Ports    []Port   `json:"Ports,omitempty"`
+}
+
+// Port is a single published port entry.
+type Port struct {
+	Protocol string `json:"Protocol"`
+	Port     uint32 `json:"Port"`
+}
+
+// Loader loads a DAB file into a kobject.KomposeObject.
+type Loader struct{}
+
+// LoadFile reads the first of the given files as a DAB bundle and converts
+// it into a KomposeObject. Only a single bundle file is supported.
+func (l Loader) LoadFile(files []string) (kobject.KomposeObject, error) {
+	komposeObject := kobject.KomposeObject{
+		ServiceConfigs: make(map[string]kobject.ServiceConfig),
+	}
+
+	if len(files) != 1 {
+		return komposeObject, fmt.Errorf("Exactly one DAB file is supported, got %d", len(files))
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		return komposeObject, fmt.Errorf("Failed to read bundle file %q: %v", files[0], err)
+	}
+
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return komposeObject, fmt.Errorf("Failed to parse bundle file %q: %v", files[0], err)
+	}
+
+	for name, svc := range b.Services {
+		log.Debugf("Converting bundle service %s", name)
+
+		ports := make([]kobject.Ports, 0, len(svc.Ports))
+		for _, p := range svc.Ports {
+			ports = append(ports, kobject.Ports{
+				HostPort:      int32(p.Port),
+				ContainerPort: int32(p.Port),
+				Protocol:      p.Protocol,
+			})
+		}
+
+		komposeObject.ServiceConfigs[name] = kobject.ServiceConfig{
+			Image:   svc.Image,
+			Command: svc.Command,
+			Network: svc.Networks,
+			Port:    ports,
+		}
+	}
+
+	return komposeObject, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleBundle = `{
+	"Version": "0.1",
+	"Services": {
+		"web": {
+			"Image": "nginx@sha256:abcd",
+			"Ports": [{"Protocol": "tcp", "Port": 80}]
+		}
+	}
+}`
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.dab")
+	if err := os.WriteFile(path, []byte(sampleBundle), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	komposeObject, err := Loader{}.LoadFile([]string{path})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	web, ok := komposeObject.ServiceConfigs["web"]
+	if !ok {
+		t.Fatal("Expected service 'web' to be present")
+	}
+	if web.Image != "nginx@sha256:abcd" {
+		t.Errorf("Expected image 'nginx@sha256:abcd', got %q", web.Image)
+	}
+	if len(web.Port) != 1 || web.Port[0].ContainerPort != 80 {
+		t.Errorf("Expected a single port 80, got %+v", web.Port)
+	}
+}
+
+func TestLoadFileRequiresSingleFile(t *testing.T) {
+	if _, err := (Loader{}).LoadFile(nil); err == nil {
+		t.Error("Expected an error when no files are given")
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import "github.com/kubernetes/kompose/pkg/kobject"
+
+// MergeOverrides applies the documented Compose override precedence across
+// files already loaded in order (docker-compose.yml, then
+// docker-compose.override.yml, then any `-f` extras): later files patch
+// earlier ones service-by-service, deep-merging environment, volumes and
+// deploy blocks rather than replacing them wholesale.
+func MergeOverrides(files []kobject.KomposeObject) kobject.KomposeObject {
+	if len(files) == 0 {
+		return kobject.KomposeObject{ServiceConfigs: map[string]kobject.ServiceConfig{}}
+	}
+
+	merged := files[0]
+	if merged.ServiceConfigs == nil {
+		merged.ServiceConfigs = map[string]kobject.ServiceConfig{}
+	}
+
+	for _, next := range files[1:] {
+		for name, override := range next.ServiceConfigs {
+			base, ok := merged.ServiceConfigs[name]
+			if !ok {
+				merged.ServiceConfigs[name] = override
+				continue
+			}
+			merged.ServiceConfigs[name] = mergeServiceConfig(base, override)
+		}
+	}
+
+	return merged
+}
+
+// mergeServiceConfig deep-merges override onto base: scalar fields are
+// replaced when set on the override, while environment, volumes and the
+// deploy block are merged key-by-key / appended, per the Compose spec.
+func mergeServiceConfig(base, override kobject.ServiceConfig) kobject.ServiceConfig {
+	merged := base
+
+	if override.Image != "" {
+		merged.Image = override.Image
+	}
+	if override.ContainerName != "" {
+		merged.ContainerName = override.ContainerName
+	}
+	if len(override.Command) > 0 {
+		merged.Command = override.Command
+	}
+	if len(override.Profiles) > 0 {
+		merged.Profiles = override.Profiles
+	}
+	if override.Replicas != 0 {
+		merged.Replicas = override.Replicas
+	}
+	if override.CPULimit != "" {
+		merged.CPULimit = override.CPULimit
+	}
+	if override.MemLimit != "" {
+		merged.MemLimit = override.MemLimit
+	}
+
+	merged.Environment = mergeEnvironment(base.Environment, override.Environment)
+	merged.Volumes = mergeVolumes(base.Volumes, override.Volumes)
+	merged.DeployConfig = mergeDeployConfig(base.DeployConfig, override.DeployConfig)
+
+	return merged
+}
+
+// mergeEnvironment deep-merges two environment lists: a variable present in
+// both keeps the override's value, while variables unique to either side
+// are kept.
+func mergeEnvironment(base, override []kobject.EnvVar) []kobject.EnvVar {
+	index := map[string]int{}
+	merged := make([]kobject.EnvVar, 0, len(base)+len(override))
+
+	for _, e := range base {
+		index[e.Name] = len(merged)
+		merged = append(merged, e)
+	}
+	for _, e := range override {
+		if i, ok := index[e.Name]; ok {
+			merged[i].Value = e.Value
+			continue
+		}
+		index[e.Name] = len(merged)
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+// mergeVolumes deep-merges two volume lists keyed by container mount path:
+// an override for the same mount path replaces the base entry, others are
+// appended.
+func mergeVolumes(base, override []kobject.Volumes) []kobject.Volumes {
+	index := map[string]int{}
+	merged := make([]kobject.Volumes, 0, len(base)+len(override))
+
+	for _, v := range base {
+		index[v.Container] = len(merged)
+		merged = append(merged, v)
+	}
+	for _, v := range override {
+		if i, ok := index[v.Container]; ok {
+			merged[i] = v
+			continue
+		}
+		index[v.Container] = len(merged)
+		merged = append(merged, v)
+	}
+	return merged
+}
+
+// mergeDeployConfig deep-merges the `deploy:` block: Replicas/Resources are
+// replaced wholesale when set on the override (they're scalar-ish), while
+// Labels is merged key-by-key.
+func mergeDeployConfig(base, override kobject.DeployConfig) kobject.DeployConfig {
+	merged := base
+
+	if override.Replicas != 0 {
+		merged.Replicas = override.Replicas
+	}
+	if override.Resource.Limit.CPU != "" || override.Resource.Limit.Memory != "" {
+		merged.Resource = override.Resource
+	}
+
+	if len(override.Labels) > 0 {
+		labels := map[string]string{}
+		for k, v := range base.Labels {
+			labels[k] = v
+		}
+		for k, v := range override.Labels {
+			labels[k] = v
+		}
+		merged.Labels = labels
+	}
+
+	return merged
+}
+
+// FilterProfiles drops services gated by a Compose `profiles:` entry whose
+// profile isn't in activeProfiles. Services with no profiles are always
+// included, matching the Compose spec's default-on behavior.
+func FilterProfiles(komposeObject kobject.KomposeObject, activeProfiles []string) kobject.KomposeObject {
+	if len(komposeObject.ServiceConfigs) == 0 {
+		return komposeObject
+	}
+
+	active := map[string]bool{}
+	for _, p := range activeProfiles {
+		active[p] = true
+	}
+
+	filtered := kobject.KomposeObject{ServiceConfigs: map[string]kobject.ServiceConfig{}}
+	for name, svc := range komposeObject.ServiceConfigs {
+		if len(svc.Profiles) == 0 {
+			filtered.ServiceConfigs[name] = svc
+			continue
+		}
+		for _, p := range svc.Profiles {
+			if active[p] {
+				filtered.ServiceConfigs[name] = svc
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compose
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+)
+
+func TestMergeOverridesDeepMergesEnvironmentVolumesAndDeploy(t *testing.T) {
+	base := kobject.KomposeObject{
+		ServiceConfigs: map[string]kobject.ServiceConfig{
+			"web": {
+				Image: "nginx:1.0",
+				Environment: []kobject.EnvVar{
+					{Name: "DEBUG", Value: "false"},
+					{Name: "REGION", Value: "us-east-1"},
+				},
+				Volumes: []kobject.Volumes{
+					{Container: "/data"},
+				},
+				DeployConfig: kobject.DeployConfig{
+					Replicas: 1,
+					Labels:   map[string]string{"tier": "web"},
+				},
+			},
+		},
+	}
+
+	override := kobject.KomposeObject{
+		ServiceConfigs: map[string]kobject.ServiceConfig{
+			"web": {
+				Environment: []kobject.EnvVar{
+					{Name: "DEBUG", Value: "true"},
+					{Name: "LOG_LEVEL", Value: "info"},
+				},
+				Volumes: []kobject.Volumes{
+					{Container: "/cache"},
+				},
+				DeployConfig: kobject.DeployConfig{
+					Replicas: 3,
+				},
+			},
+		},
+	}
+
+	merged := MergeOverrides([]kobject.KomposeObject{base, override})
+	web := merged.ServiceConfigs["web"]
+
+	if web.Image != "nginx:1.0" {
+		t.Errorf("Expected base image to be preserved, got %q", web.Image)
+	}
+	if web.DeployConfig.Replicas != 3 {
+		t.Errorf("Expected override replicas 3, got %d", web.DeployConfig.Replicas)
+	}
+	if !reflect.DeepEqual(web.DeployConfig.Labels, map[string]string{"tier": "web"}) {
+		t.Errorf("Expected base labels to be preserved, got %+v", web.DeployConfig.Labels)
+	}
+
+	wantEnv := map[string]string{"DEBUG": "true", "REGION": "us-east-1", "LOG_LEVEL": "info"}
+	gotEnv := map[string]string{}
+	for _, e := range web.Environment {
+		gotEnv[e.Name] = e.Value
+	}
+	if !reflect.DeepEqual(gotEnv, wantEnv) {
+		t.Errorf("Expected merged environment %+v, got %+v", wantEnv, gotEnv)
+	}
+
+	wantVolumes := map[string]bool{"/data": true, "/cache": true}
+	for _, v := range web.Volumes {
+		if !wantVolumes[v.Container] {
+			t.Errorf("Unexpected volume mount %q", v.Container)
+		}
+		delete(wantVolumes, v.Container)
+	}
+	if len(wantVolumes) != 0 {
+		t.Errorf("Missing volume mounts: %+v", wantVolumes)
+	}
+}
+
+func TestFilterProfiles(t *testing.T) {
+	komposeObject := kobject.KomposeObject{
+		ServiceConfigs: map[string]kobject.ServiceConfig{
+			"web":   {},
+			"debug": {Profiles: []string{"debug"}},
+			"perf":  {Profiles: []string{"perf", "debug"}},
+		},
+	}
+
+	filtered := FilterProfiles(komposeObject, []string{"debug"})
+
+	if _, ok := filtered.ServiceConfigs["web"]; !ok {
+		t.Error("Expected service with no profiles to always be included")
+	}
+	if _, ok := filtered.ServiceConfigs["debug"]; !ok {
+		t.Error("Expected service activated by --profile=debug to be included")
+	}
+	if _, ok := filtered.ServiceConfigs["perf"]; !ok {
+		t.Error("Expected service matching one of several profiles to be included")
+	}
+
+	filteredNone := FilterProfiles(komposeObject, nil)
+	if _, ok := filteredNone.ServiceConfigs["debug"]; ok {
+		t.Error("Expected profile-gated service to be excluded when no profiles are active")
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"fmt"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+)
+
+// Loader parses one or more input files into a kobject.KomposeObject.
+type Loader interface {
+	LoadFile(files []string) (kobject.KomposeObject, error)
+}
+
+var loaders = map[string]Loader{}
+
+// Register makes a loader available via --input-format=<name> (or by
+// auto-detection resolving to that name). Out-of-tree loaders call this
+// from an init() func, the same way transformer.Register works.
+func Register(name string, l Loader) {
+	loaders[name] = l
+}
+
+// GetLoader looks up a registered loader by input format name.
+func GetLoader(name string) (Loader, error) {
+	l, ok := loaders[name]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported input format: %q", name)
+	}
+	return l, nil
+}
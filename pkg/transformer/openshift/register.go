@@ -0,0 +1,32 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"github.com/kubernetes/kompose/pkg/kobject"
+	"github.com/kubernetes/kompose/pkg/transformer"
+	"github.com/kubernetes/kompose/pkg/transformer/kubernetes"
+)
+
+func init() {
+	transformer.Register("openshift", transformer.Provider{
+		Factory: func(opt kobject.ConvertOptions) transformer.Transformer {
+			return &OpenShift{Kubernetes: kubernetes.Kubernetes{Opt: opt}}
+		},
+		Flags: []string{"deployment-config", "build-repo", "build-branch", "as-template"},
+	})
+}
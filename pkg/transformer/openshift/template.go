@@ -0,0 +1,183 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	templateapi "github.com/openshift/api/template/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+)
+
+// WrapAsTemplate wraps objects in an OpenShift Template named name, hoisting
+// compose environment variables and image references into
+// Template.parameters with `${VAR}` substitution in the object bodies, so
+// the result can be reused per-environment via `oc process | oc apply`.
+func WrapAsTemplate(objects []runtime.Object, komposeObject kobject.KomposeObject, name string) ([]runtime.Object, error) {
+	params := templateParameters(komposeObject)
+
+	substituted, err := substituteParamRefs(objects, komposeObject)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]runtime.RawExtension, 0, len(substituted))
+	for _, obj := range substituted {
+		raw = append(raw, runtime.RawExtension{Object: obj})
+	}
+
+	tmpl := &templateapi.Template{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Template",
+			APIVersion: "template.openshift.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Objects:    raw,
+		Parameters: params,
+	}
+
+	return []runtime.Object{tmpl}, nil
+}
+
+// substituteParamRefs rewrites each object's container image and env values
+// into `${PARAM}` references matching the parameters templateParameters
+// derives for the same compose service, so the wrapped objects are actually
+// parameterized rather than carrying their original static values. Objects
+// are matched to a service by name, which is how kompose names the objects
+// it generates for a given compose service; objects with no matching
+// service (or nothing to substitute) pass through unchanged.
+func substituteParamRefs(objects []runtime.Object, komposeObject kobject.KomposeObject) ([]runtime.Object, error) {
+	out := make([]runtime.Object, 0, len(objects))
+	for _, obj := range objects {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		svc, ok := komposeObject.ServiceConfigs[accessor.GetName()]
+		if !ok {
+			out = append(out, obj)
+			continue
+		}
+
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil, err
+		}
+		substituteContainerRefs(m, accessor.GetName(), svc)
+		out = append(out, &unstructured.Unstructured{Object: m})
+	}
+	return out, nil
+}
+
+// substituteContainerRefs substitutes the first container's image and any
+// env values that have a matching parameter, when present.
+func substituteContainerRefs(m map[string]interface{}, name string, svc kobject.ServiceConfig) {
+	containers, found, _ := unstructured.NestedSlice(m, "spec", "template", "spec", "containers")
+	if !found || len(containers) == 0 {
+		return
+	}
+	container, ok := containers[0].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	if svc.Image != "" {
+		container["image"] = fmt.Sprintf("${%s_IMAGE}", envSafe(name))
+	}
+
+	hasParam := make(map[string]bool, len(svc.Environment))
+	for _, e := range svc.Environment {
+		hasParam[e.Name] = true
+	}
+
+	if env, found := container["env"].([]interface{}); found {
+		for _, e := range env {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if envName, _ := entry["name"].(string); hasParam[envName] {
+				entry["value"] = fmt.Sprintf("${%s}", envParamName(name, envName))
+			}
+		}
+	}
+
+	containers[0] = container
+	_ = unstructured.SetNestedSlice(m, containers, "spec", "template", "spec", "containers")
+}
+
+// templateParameters derives one Template parameter per environment variable
+// and image reference found across the compose services. Environment
+// variables are scoped by service (e.g. PORT on service "web" becomes
+// WEB_PORT) rather than by bare name, so two services sharing an env var
+// name don't collapse onto one shared parameter and silently lose one
+// side's value.
+func templateParameters(komposeObject kobject.KomposeObject) []templateapi.Parameter {
+	seen := map[string]bool{}
+	var params []templateapi.Parameter
+
+	addParam := func(name, value string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		params = append(params, templateapi.Parameter{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	names := make([]string, 0, len(komposeObject.ServiceConfigs))
+	for name := range komposeObject.ServiceConfigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		svc := komposeObject.ServiceConfigs[name]
+		if svc.Image != "" {
+			addParam(fmt.Sprintf("%s_IMAGE", envSafe(name)), svc.Image)
+		}
+		for _, env := range svc.Environment {
+			addParam(envParamName(name, env.Name), env.Value)
+		}
+	}
+
+	return params
+}
+
+// envSafe upper-cases a compose service name for use as a parameter prefix.
+func envSafe(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// envParamName scopes an environment variable's parameter name by service,
+// so the same variable name on different services doesn't get merged into
+// a single shared parameter.
+func envParamName(serviceName, envName string) string {
+	return fmt.Sprintf("%s_%s", envSafe(serviceName), envName)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package transformer
+
+import (
+	"sort"
+
+	"github.com/kubernetes/kompose/pkg/kobject"
+)
+
+// Factory creates a Transformer for the given CLI options.
+type Factory func(kobject.ConvertOptions) Transformer
+
+// Provider describes a registered --provider=<name> target: how to build
+// its Transformer, and which CLI flags are exclusively its own.
+type Provider struct {
+	Factory Factory
+	// Flags are the flag names that only make sense for this provider, so
+	// ValidateFlags can reject them when a different provider is selected.
+	Flags []string
+}
+
+var providers = map[string]Provider{}
+
+// Register makes a provider available via --provider=<name>. Out-of-tree
+// providers (a Nomad HCL emitter, a KWOK stage-config emitter, a CRD-based
+// operator, ...) call this from an init() func.
+func Register(name string, provider Provider) {
+	providers[name] = provider
+}
+
+// Get looks up a registered provider by name.
+func Get(name string) (Provider, bool) {
+	p, ok := providers[name]
+	return p, ok
+}
+
+// Names returns the names of all registered providers, sorted.
+func Names() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}